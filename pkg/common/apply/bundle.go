@@ -0,0 +1,235 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// kindOrder ranks well-known kinds so a BundleApplier applies Namespaces
+// before CRDs, CRDs before RBAC, and RBAC before everything else (mirroring
+// the ordering cli-utils uses for pruning). Kinds not listed here are ranked
+// last, alongside workloads.
+var kindOrder = map[string]int{
+	"Namespace":                0,
+	"CustomResourceDefinition": 1,
+	"ClusterRole":              2,
+	"ClusterRoleBinding":       2,
+	"Role":                     2,
+	"RoleBinding":              2,
+	"ServiceAccount":           2,
+}
+
+func rankForGVK(gvk schema.GroupVersionKind) int {
+	if rank, ok := kindOrder[gvk.Kind]; ok {
+		return rank
+	}
+	return 3
+}
+
+// ObjectStatus reports what happened to a single BundleItem during a
+// BundleApplier.Apply call.
+type ObjectStatus string
+
+const (
+	ObjectCreated   ObjectStatus = "Created"
+	ObjectUpdated   ObjectStatus = "Updated"
+	ObjectUnchanged ObjectStatus = "Unchanged"
+	ObjectErrored   ObjectStatus = "Errored"
+)
+
+// ObjectResult is the outcome of applying a single BundleItem. Object is the
+// object returned by the underlying Applier: the live object after a real
+// apply, or the object Server-Side Apply / the CompareFunc would have
+// produced when Status came from a dry-run, so callers can inspect what
+// would have changed without the bundle having mutated anything.
+type ObjectResult struct {
+	GVK    schema.GroupVersionKind
+	Name   string
+	Status ObjectStatus
+	Object runtime.Object
+	Err    error
+}
+
+// BundleResult aggregates the per-object results of a BundleApplier.Apply
+// call.
+type BundleResult struct {
+	Results []ObjectResult
+}
+
+// Created returns the subset of Results with status ObjectCreated.
+func (r *BundleResult) Created() []ObjectResult { return r.filter(ObjectCreated) }
+
+// Updated returns the subset of Results with status ObjectUpdated.
+func (r *BundleResult) Updated() []ObjectResult { return r.filter(ObjectUpdated) }
+
+// Errored returns the subset of Results with status ObjectErrored.
+func (r *BundleResult) Errored() []ObjectResult { return r.filter(ObjectErrored) }
+
+func (r *BundleResult) filter(status ObjectStatus) []ObjectResult {
+	var out []ObjectResult
+	for _, result := range r.Results {
+		if result.Status == status {
+			out = append(out, result)
+		}
+	}
+	return out
+}
+
+// BundleItem is a single object to apply as part of a bundle, together with
+// the GVK used to order it and the closure that actually applies it.
+type BundleItem struct {
+	GVK  schema.GroupVersionKind
+	Name string
+
+	apply func(ctx context.Context, recorder events.Recorder, dryRun bool) (ObjectStatus, runtime.Object, error)
+}
+
+// NewBundleItem wraps a typed Applier call so it can be scheduled by a
+// BundleApplier alongside items of other, unrelated types.
+func NewBundleItem[T runtime.Object](gvk schema.GroupVersionKind, name string, getter Getter[T], applier Applier[T], required T) BundleItem {
+	return BundleItem{
+		GVK:  gvk,
+		Name: name,
+		apply: func(ctx context.Context, recorder events.Recorder, dryRun bool) (ObjectStatus, runtime.Object, error) {
+			_, err := getter.Get(name)
+			existed := err == nil
+			if err != nil && !errors.IsNotFound(err) {
+				return ObjectErrored, nil, err
+			}
+
+			var (
+				actual   runtime.Object
+				modified bool
+				applyErr error
+			)
+			if dryRun {
+				dryRunApplier, ok := applier.(DryRunApplier[T])
+				if !ok {
+					return ObjectErrored, nil, fmt.Errorf("applier for %s does not support dry-run", gvk.Kind)
+				}
+				actual, modified, applyErr = dryRunApplier.ApplyDryRun(ctx, required)
+			} else {
+				actual, modified, applyErr = applier.Apply(ctx, required, recorder)
+			}
+
+			switch {
+			case applyErr != nil:
+				return ObjectErrored, actual, applyErr
+			case !modified:
+				return ObjectUnchanged, actual, nil
+			case !existed:
+				return ObjectCreated, actual, nil
+			default:
+				return ObjectUpdated, actual, nil
+			}
+		},
+	}
+}
+
+// BundleApplier applies a heterogeneous set of BundleItems as a single unit:
+// it orders them topologically by GVK, fans the apply of each ordering rank
+// out across a bounded pool of workers, and can run the whole bundle as a
+// dry-run.
+type BundleApplier struct {
+	workers int
+	dryRun  bool
+}
+
+// BundleApplierOption configures a BundleApplier constructed via
+// NewBundleApplier.
+type BundleApplierOption func(*BundleApplier)
+
+// WithWorkers bounds how many BundleItems are applied concurrently within a
+// single ordering rank. The default is 1, i.e. fully sequential.
+func WithWorkers(workers int) BundleApplierOption {
+	return func(b *BundleApplier) {
+		b.workers = workers
+	}
+}
+
+// WithDryRun makes Apply simulate the bundle instead of mutating the
+// cluster; every BundleItem's Applier must implement DryRunApplier.
+func WithDryRun(dryRun bool) BundleApplierOption {
+	return func(b *BundleApplier) {
+		b.dryRun = dryRun
+	}
+}
+
+// NewBundleApplier returns a BundleApplier with the given options applied on
+// top of sane defaults (a single worker, no dry-run).
+func NewBundleApplier(opts ...BundleApplierOption) *BundleApplier {
+	b := &BundleApplier{workers: 1}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.workers < 1 {
+		b.workers = 1
+	}
+	return b
+}
+
+// Apply applies items in topological order by GVK (Namespaces, then CRDs,
+// then RBAC, then everything else). Within a single rank, items are applied
+// concurrently across up to b.workers goroutines; ranks themselves are
+// applied one at a time so, e.g., a Namespace is guaranteed to land before
+// any workload that lives in it.
+func (b *BundleApplier) Apply(ctx context.Context, recorder events.Recorder, items []BundleItem) *BundleResult {
+	result := &BundleResult{}
+	for _, rank := range groupByRank(items) {
+		result.Results = append(result.Results, b.applyRank(ctx, recorder, rank)...)
+	}
+
+	recorder.Eventf("BundleApplied", "Applied bundle of %d objects: %d created, %d updated, %d errored",
+		len(result.Results), len(result.Created()), len(result.Updated()), len(result.Errored()))
+
+	return result
+}
+
+func groupByRank(items []BundleItem) [][]BundleItem {
+	byRank := map[int][]BundleItem{}
+	for _, item := range items {
+		rank := rankForGVK(item.GVK)
+		byRank[rank] = append(byRank[rank], item)
+	}
+
+	ranks := make([]int, 0, len(byRank))
+	for rank := range byRank {
+		ranks = append(ranks, rank)
+	}
+	sort.Ints(ranks)
+
+	ordered := make([][]BundleItem, 0, len(ranks))
+	for _, rank := range ranks {
+		ordered = append(ordered, byRank[rank])
+	}
+	return ordered
+}
+
+func (b *BundleApplier) applyRank(ctx context.Context, recorder events.Recorder, items []BundleItem) []ObjectResult {
+	results := make([]ObjectResult, len(items))
+
+	sem := make(chan struct{}, b.workers)
+	var wg sync.WaitGroup
+	for i := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			item := items[i]
+			status, object, err := item.apply(ctx, recorder, b.dryRun)
+			results[i] = ObjectResult{GVK: item.GVK, Name: item.Name, Status: status, Object: object, Err: err}
+		}(i)
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,151 @@
+package apply
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// recordingBundleApplier is an Applier[runtime.Object] that records the
+// order and concurrency of calls it receives, for testing BundleApplier
+// ranking and worker bounds.
+type recordingBundleApplier struct {
+	mu          sync.Mutex
+	order       []string
+	inflight    int32
+	maxInFlight int32
+
+	dryRunObject runtime.Object
+}
+
+func (a *recordingBundleApplier) Apply(_ context.Context, required runtime.Object, _ events.Recorder) (runtime.Object, bool, error) {
+	cur := atomic.AddInt32(&a.inflight, 1)
+	defer atomic.AddInt32(&a.inflight, -1)
+	for {
+		max := atomic.LoadInt32(&a.maxInFlight)
+		if cur <= max || atomic.CompareAndSwapInt32(&a.maxInFlight, max, cur) {
+			break
+		}
+	}
+
+	a.mu.Lock()
+	a.order = append(a.order, required.(*namedObject).name)
+	a.mu.Unlock()
+
+	return required, true, nil
+}
+
+func (a *recordingBundleApplier) ApplyDryRun(_ context.Context, required runtime.Object) (runtime.Object, bool, error) {
+	return a.dryRunObject, true, nil
+}
+
+// namedObject is a minimal runtime.Object stand-in carrying just a name, so
+// tests can identify which BundleItem an Apply call came from.
+type namedObject struct {
+	runtime.Object
+	name string
+}
+
+type missingGetter struct{}
+
+func (missingGetter) Get(name string) (runtime.Object, error) {
+	return nil, errors.NewNotFound(schema.GroupResource{Resource: "things"}, name)
+}
+
+func TestBundleApplierOrdersByKind(t *testing.T) {
+	applier := &recordingBundleApplier{}
+	getter := missingGetter{}
+
+	items := []BundleItem{
+		NewBundleItem[runtime.Object](schema.GroupVersionKind{Kind: "Deployment"}, "workload", getter, applier, &namedObject{name: "workload"}),
+		NewBundleItem[runtime.Object](schema.GroupVersionKind{Kind: "ClusterRole"}, "role", getter, applier, &namedObject{name: "role"}),
+		NewBundleItem[runtime.Object](schema.GroupVersionKind{Kind: "Namespace"}, "ns", getter, applier, &namedObject{name: "ns"}),
+		NewBundleItem[runtime.Object](schema.GroupVersionKind{Kind: "CustomResourceDefinition"}, "crd", getter, applier, &namedObject{name: "crd"}),
+	}
+
+	b := NewBundleApplier()
+	result := b.Apply(context.Background(), newRecorder(), items)
+
+	if len(result.Errored()) != 0 {
+		t.Fatalf("expected no errors, got %v", result.Errored())
+	}
+	want := []string{"ns", "crd", "role", "workload"}
+	if len(applier.order) != len(want) {
+		t.Fatalf("expected %d calls, got %v", len(want), applier.order)
+	}
+	for i, name := range want {
+		if applier.order[i] != name {
+			t.Fatalf("expected rank order %v, got %v", want, applier.order)
+		}
+	}
+}
+
+func TestBundleApplierBoundsConcurrencyWithinRank(t *testing.T) {
+	applier := &recordingBundleApplier{}
+	getter := missingGetter{}
+
+	var items []BundleItem
+	for i := 0; i < 10; i++ {
+		name := string(rune('a' + i))
+		items = append(items, NewBundleItem[runtime.Object](schema.GroupVersionKind{Kind: "Secret"}, name, getter, applier, &namedObject{name: name}))
+	}
+
+	b := NewBundleApplier(WithWorkers(3))
+	b.Apply(context.Background(), newRecorder(), items)
+
+	if applier.maxInFlight > 3 {
+		t.Fatalf("expected at most 3 concurrent Apply calls, observed %d", applier.maxInFlight)
+	}
+}
+
+func TestBundleApplierDryRunSurfacesObjectWithoutMutating(t *testing.T) {
+	dryRunResult := &namedObject{name: "would-be-applied"}
+	applier := &recordingBundleApplier{dryRunObject: dryRunResult}
+	getter := missingGetter{}
+
+	items := []BundleItem{
+		NewBundleItem[runtime.Object](schema.GroupVersionKind{Kind: "ConfigMap"}, "cm", getter, applier, &namedObject{name: "cm"}),
+	}
+
+	b := NewBundleApplier(WithDryRun(true))
+	result := b.Apply(context.Background(), newRecorder(), items)
+
+	if len(applier.order) != 0 {
+		t.Fatalf("expected the real Apply to never be called during dry-run, got %v", applier.order)
+	}
+	if len(result.Results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(result.Results))
+	}
+	if result.Results[0].Object != runtime.Object(dryRunResult) {
+		t.Fatalf("expected ObjectResult.Object to carry the dry-run object, got %v", result.Results[0].Object)
+	}
+}
+
+func TestBundleApplierDryRunErrorsWithoutDryRunApplier(t *testing.T) {
+	applier := &nonDryRunApplier{}
+	getter := missingGetter{}
+
+	items := []BundleItem{
+		NewBundleItem[runtime.Object](schema.GroupVersionKind{Kind: "ConfigMap"}, "cm", getter, applier, &namedObject{name: "cm"}),
+	}
+
+	b := NewBundleApplier(WithDryRun(true))
+	result := b.Apply(context.Background(), newRecorder(), items)
+
+	if len(result.Errored()) != 1 {
+		t.Fatalf("expected the item to error out since its Applier has no dry-run support, got %v", result.Results)
+	}
+}
+
+// nonDryRunApplier implements Applier but not DryRunApplier.
+type nonDryRunApplier struct{}
+
+func (nonDryRunApplier) Apply(_ context.Context, required runtime.Object, _ events.Recorder) (runtime.Object, bool, error) {
+	return required, true, nil
+}
@@ -0,0 +1,258 @@
+package apply
+
+import (
+	"encoding/json"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// LastAppliedConfigAnnotation records the configuration that was last
+// applied through one of the CompareFuncs below, mirroring kubectl apply, so
+// the next reconcile can compute a proper three-way diff instead of only
+// comparing the required object against the live one.
+const LastAppliedConfigAnnotation = "apply.open-cluster-management.io/last-applied-configuration"
+
+// serverPopulatedMetadataFields are cleared from both the last-applied and
+// the live object before diffing, since the apiserver owns them and they
+// would otherwise show up as a spurious difference on every reconcile.
+var serverPopulatedMetadataFields = []string{"resourceVersion", "managedFields", "creationTimestamp", "uid"}
+
+// sanitizeForDiff marshals obj to JSON and strips status and the
+// server-populated metadata fields, so what's left is exactly what a caller
+// could have set through an apply.
+func sanitizeForDiff(obj runtime.Object) ([]byte, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic map[string]interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, err
+	}
+
+	delete(generic, "status")
+	if metadata, ok := generic["metadata"].(map[string]interface{}); ok {
+		for _, field := range serverPopulatedMetadataFields {
+			delete(metadata, field)
+		}
+	}
+
+	return json.Marshal(generic)
+}
+
+func lastAppliedConfig(existing runtime.Object) ([]byte, error) {
+	accessor, err := meta.Accessor(existing)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(accessor.GetAnnotations()[LastAppliedConfigAnnotation]), nil
+}
+
+func setLastAppliedConfig(obj runtime.Object, raw []byte) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	annotations := accessor.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[LastAppliedConfigAnnotation] = string(raw)
+	accessor.SetAnnotations(annotations)
+
+	return nil
+}
+
+// StrategicMergeCompare returns a CompareFunc for built-in types that
+// computes a kubectl-style three-way strategic merge patch from the
+// last-applied configuration (see LastAppliedConfigAnnotation), the live
+// object and required, and applies it on top of the live object. dataStruct
+// is a zero-valued instance of T used to look up its patch metadata, e.g.
+// StrategicMergeCompare[*appsv1.Deployment](&appsv1.Deployment{}).
+func StrategicMergeCompare[T runtime.Object](dataStruct T) CompareFunc[T] {
+	return func(required, existing T) (T, bool) {
+		var zero T
+
+		requiredJSON, err := sanitizeForDiff(required)
+		if err != nil {
+			return zero, false
+		}
+		existingJSON, err := sanitizeForDiff(existing)
+		if err != nil {
+			return zero, false
+		}
+		lastApplied, err := lastAppliedConfig(existing)
+		if err != nil {
+			return zero, false
+		}
+
+		var patch []byte
+		if len(lastApplied) == 0 {
+			patch, err = strategicpatch.CreateTwoWayMergePatch(existingJSON, requiredJSON, dataStruct)
+		} else {
+			patchMeta, metaErr := strategicpatch.NewPatchMetaFromStruct(dataStruct)
+			if metaErr != nil {
+				return zero, false
+			}
+			patch, err = strategicpatch.CreateThreeWayMergePatch(lastApplied, requiredJSON, existingJSON, patchMeta, true)
+		}
+		if err != nil {
+			return zero, false
+		}
+		if string(patch) == "{}" {
+			return existing, false
+		}
+
+		mergedJSON, err := strategicpatch.StrategicMergePatch(existingJSON, patch, dataStruct)
+		if err != nil {
+			return zero, false
+		}
+
+		merged, ok := existing.DeepCopyObject().(T)
+		if !ok {
+			return zero, false
+		}
+		if err := json.Unmarshal(mergedJSON, merged); err != nil {
+			return zero, false
+		}
+		if err := setLastAppliedConfig(merged, requiredJSON); err != nil {
+			return zero, false
+		}
+
+		return merged, true
+	}
+}
+
+// JSONMergeCompare returns a CompareFunc for CRDs and other types without
+// generated strategic-merge-patch metadata. It diffs the last-applied
+// configuration against required using an RFC 7396 JSON merge patch and
+// applies that patch on top of the live object.
+func JSONMergeCompare[T runtime.Object]() CompareFunc[T] {
+	return func(required, existing T) (T, bool) {
+		var zero T
+
+		requiredJSON, err := sanitizeForDiff(required)
+		if err != nil {
+			return zero, false
+		}
+		existingJSON, err := sanitizeForDiff(existing)
+		if err != nil {
+			return zero, false
+		}
+		lastApplied, err := lastAppliedConfig(existing)
+		if err != nil {
+			return zero, false
+		}
+
+		base := existingJSON
+		if len(lastApplied) > 0 {
+			base = lastApplied
+		}
+
+		patch, err := jsonpatch.CreateMergePatch(base, requiredJSON)
+		if err != nil {
+			return zero, false
+		}
+		if string(patch) == "{}" {
+			return existing, false
+		}
+
+		mergedJSON, err := jsonpatch.MergePatch(existingJSON, patch)
+		if err != nil {
+			return zero, false
+		}
+
+		merged, ok := existing.DeepCopyObject().(T)
+		if !ok {
+			return zero, false
+		}
+		if err := json.Unmarshal(mergedJSON, merged); err != nil {
+			return zero, false
+		}
+		if err := setLastAppliedConfig(merged, requiredJSON); err != nil {
+			return zero, false
+		}
+
+		return merged, true
+	}
+}
+
+// MetadataOnlyCompare returns a CompareFunc that leaves spec and status
+// untouched and only reconciles labels, annotations and owner references.
+// It's for callers that manage an object's content elsewhere but still want
+// this package to own its metadata.
+func MetadataOnlyCompare[T runtime.Object]() CompareFunc[T] {
+	return func(required, existing T) (T, bool) {
+		var zero T
+
+		requiredAccessor, err := meta.Accessor(required)
+		if err != nil {
+			return zero, false
+		}
+		existingAccessor, err := meta.Accessor(existing)
+		if err != nil {
+			return zero, false
+		}
+
+		needsLabels := !equalStringMaps(requiredAccessor.GetLabels(), existingAccessor.GetLabels())
+		needsAnnotations := !equalStringMaps(requiredAccessor.GetAnnotations(), existingAccessor.GetAnnotations())
+		needsOwnerRefs := !equalOwnerReferences(requiredAccessor.GetOwnerReferences(), existingAccessor.GetOwnerReferences())
+		if !needsLabels && !needsAnnotations && !needsOwnerRefs {
+			return existing, false
+		}
+
+		// existing is typically sourced from an informer lister cache, so it
+		// must be copied before mutating; writing through to the cached
+		// object would corrupt it for every other reader.
+		updated, ok := existing.DeepCopyObject().(T)
+		if !ok {
+			return zero, false
+		}
+		updatedAccessor, err := meta.Accessor(updated)
+		if err != nil {
+			return zero, false
+		}
+
+		if needsLabels {
+			updatedAccessor.SetLabels(requiredAccessor.GetLabels())
+		}
+		if needsAnnotations {
+			updatedAccessor.SetAnnotations(requiredAccessor.GetAnnotations())
+		}
+		if needsOwnerRefs {
+			updatedAccessor.SetOwnerReferences(requiredAccessor.GetOwnerReferences())
+		}
+
+		return updated, true
+	}
+}
+
+func equalStringMaps(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func equalOwnerReferences(a, b []metav1.OwnerReference) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
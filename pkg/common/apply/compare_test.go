@@ -0,0 +1,110 @@
+package apply
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMetadataOnlyCompareDoesNotMutateExisting(t *testing.T) {
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cm",
+			Labels: map[string]string{"foo": "bar"},
+		},
+	}
+	required := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cm",
+			Labels: map[string]string{"foo": "baz"},
+		},
+	}
+
+	updated, modified := MetadataOnlyCompare[*corev1.ConfigMap]()(required, existing)
+	if !modified {
+		t.Fatalf("expected modified to be true")
+	}
+	if updated == existing {
+		t.Fatalf("expected a copy of existing to be returned, got the same pointer")
+	}
+	if existing.Labels["foo"] != "bar" {
+		t.Fatalf("existing was mutated in place: labels = %v", existing.Labels)
+	}
+	if updated.Labels["foo"] != "baz" {
+		t.Fatalf("updated did not pick up the required label: labels = %v", updated.Labels)
+	}
+}
+
+func TestMetadataOnlyCompareNoChangeNoCopy(t *testing.T) {
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cm",
+			Labels: map[string]string{"foo": "bar"},
+		},
+	}
+	required := existing.DeepCopy()
+
+	updated, modified := MetadataOnlyCompare[*corev1.ConfigMap]()(required, existing)
+	if modified {
+		t.Fatalf("expected modified to be false")
+	}
+	if updated != existing {
+		t.Fatalf("expected the unmodified case to return existing itself")
+	}
+}
+
+func TestStrategicMergeCompareFirstApplyRecordsLastApplied(t *testing.T) {
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm"},
+		Data:       map[string]string{"a": "1"},
+	}
+	required := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm"},
+		Data:       map[string]string{"a": "1", "b": "2"},
+	}
+
+	compare := StrategicMergeCompare[*corev1.ConfigMap](&corev1.ConfigMap{})
+	updated, modified := compare(required, existing)
+	if !modified {
+		t.Fatalf("expected modified to be true")
+	}
+	if updated.Data["b"] != "2" {
+		t.Fatalf("expected merged data to contain the new key, got %v", updated.Data)
+	}
+	if updated.Annotations[LastAppliedConfigAnnotation] == "" {
+		t.Fatalf("expected last-applied-configuration annotation to be recorded")
+	}
+}
+
+func TestStrategicMergeCompareNoChange(t *testing.T) {
+	obj := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm"},
+		Data:       map[string]string{"a": "1"},
+	}
+
+	compare := StrategicMergeCompare[*corev1.ConfigMap](&corev1.ConfigMap{})
+	_, modified := compare(obj.DeepCopy(), obj)
+	if modified {
+		t.Fatalf("expected modified to be false when required matches existing")
+	}
+}
+
+func TestJSONMergeCompare(t *testing.T) {
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm"},
+		Data:       map[string]string{"a": "1"},
+	}
+	required := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm"},
+		Data:       map[string]string{"a": "1", "b": "2"},
+	}
+
+	updated, modified := JSONMergeCompare[*corev1.ConfigMap]()(required, existing)
+	if !modified {
+		t.Fatalf("expected modified to be true")
+	}
+	if updated.Data["b"] != "2" {
+		t.Fatalf("expected merged data to contain the new key, got %v", updated.Data)
+	}
+}
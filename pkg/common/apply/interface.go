@@ -3,14 +3,31 @@ package apply
 import (
 	"context"
 	"fmt"
+	"time"
+
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/openshift/library-go/pkg/operator/resource/resourcehelper"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
 )
 
+// defaultMaxConflictRetries is how many times Apply retries an Update that
+// failed because of a resource version conflict, absent WithConflictRetry.
+const defaultMaxConflictRetries = 5
+
+// defaultConflictBackoff is the backoff between conflict retries, absent
+// WithBackoff.
+var defaultConflictBackoff = wait.Backoff{
+	Duration: 10 * time.Millisecond,
+	Factor:   2.0,
+	Steps:    defaultMaxConflictRetries,
+}
+
 // Getter is a wrapper interface of lister
 type Getter[T runtime.Object] interface {
 	Get(name string) (T, error)
@@ -31,21 +48,57 @@ type Applier[T runtime.Object] interface {
 	Apply(ctx context.Context, required T, recorder events.Recorder) (runtime.Object, bool, error)
 }
 
+// DryRunApplier is implemented by Appliers that can simulate an Apply without
+// mutating cluster state, by asking the apiserver to validate and merge the
+// request without persisting it.
+type DryRunApplier[T runtime.Object] interface {
+	ApplyDryRun(ctx context.Context, required T) (runtime.Object, bool, error)
+}
+
 // applier implements Applier
 type applier[T runtime.Object] struct {
 	getter  Getter[T]
 	client  Client[T]
 	compare CompareFunc[T]
+
+	maxConflictRetries int
+	backoff            wait.Backoff
 }
 
-func NewApplier[T runtime.Object](getter Getter[T], client Client[T], compareFunc CompareFunc[T]) Applier[T] {
-	return &applier[T]{
-		getter:  getter,
-		client:  client,
-		compare: compareFunc,
+// ApplierOption configures an Applier constructed via NewApplier.
+type ApplierOption[T runtime.Object] func(*applier[T])
+
+// WithConflictRetry bounds how many times Apply will re-Get, re-compare and
+// re-Update an object after a conflicting write, instead of returning the
+// conflict straight to the caller. Pass 0 to disable retries.
+func WithConflictRetry[T runtime.Object](maxRetries int) ApplierOption[T] {
+	return func(a *applier[T]) {
+		a.maxConflictRetries = maxRetries
+	}
+}
+
+// WithBackoff overrides the backoff used between conflict retries.
+func WithBackoff[T runtime.Object](backoff wait.Backoff) ApplierOption[T] {
+	return func(a *applier[T]) {
+		a.backoff = backoff
 	}
 }
 
+func NewApplier[T runtime.Object](getter Getter[T], client Client[T], compareFunc CompareFunc[T], opts ...ApplierOption[T]) Applier[T] {
+	a := &applier[T]{
+		getter:             getter,
+		client:             client,
+		compare:            compareFunc,
+		maxConflictRetries: defaultMaxConflictRetries,
+		backoff:            defaultConflictBackoff,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
 func (a *applier[T]) Apply(ctx context.Context, required T, recorder events.Recorder) (runtime.Object, bool, error) {
 	requiredAccessor, err := meta.Accessor(required)
 	if err != nil {
@@ -72,13 +125,87 @@ func (a *applier[T]) Apply(ctx context.Context, required T, recorder events.Reco
 		return updated, modified, nil
 	}
 
-	updated, err = a.client.Update(ctx, updated, metav1.UpdateOptions{})
+	updated, modified, err = a.update(ctx, gvk, required, updated, recorder)
 	switch {
 	case err != nil:
 		recorder.Warningf(fmt.Sprintf("%sUpdateFailed", gvk.Kind), "Failed to update %s: %v", resourcehelper.FormatResourceForCLIWithNamespace(required), err)
-	default:
-		recorder.Eventf(fmt.Sprintf("%sUpdated", gvk.Kind), "Updated %s:\n%s", resourcehelper.FormatResourceForCLIWithNamespace(updated))
+	case modified:
+		recorder.Eventf(fmt.Sprintf("%sUpdated", gvk.Kind), "Updated %s", resourcehelper.FormatResourceForCLIWithNamespace(updated))
+	}
+
+	return updated, modified, err
+}
+
+// update issues the Update call for updated, retrying on conflict up to
+// a.maxConflictRetries times via client-go's retry.RetryOnConflict: each
+// retry re-fetches the object and re-runs a.compare against the fresh
+// version before re-issuing Update. This absorbs contention on hot resources
+// that are edited by more than one controller without forcing the caller to
+// redo the whole reconcile. The returned bool reflects whether the object
+// ended up modified; if a conflicting write already applied required's
+// changes, a retry's compare can come back unmodified even though the
+// pre-retry compare that triggered this call said otherwise.
+func (a *applier[T]) update(ctx context.Context, gvk schema.GroupVersionKind, required, updated T, recorder events.Recorder) (T, bool, error) {
+	result, err := a.client.Update(ctx, updated, metav1.UpdateOptions{})
+	if err == nil || !errors.IsConflict(err) || a.maxConflictRetries <= 0 {
+		return result, err == nil, err
+	}
+
+	requiredAccessor, accessorErr := meta.Accessor(required)
+	if accessorErr != nil {
+		return result, false, accessorErr
+	}
+
+	modified := true
+	attempt := 0
+	backoff := a.backoff
+	backoff.Steps = a.maxConflictRetries
+
+	retryErr := retry.RetryOnConflict(backoff, func() error {
+		attempt++
+
+		existing, getErr := a.getter.Get(requiredAccessor.GetName())
+		if getErr != nil {
+			return getErr
+		}
+
+		retryUpdate, stillModified := a.compare(required, existing)
+		if !stillModified {
+			result, modified = retryUpdate, false
+			return nil
+		}
+
+		recorder.Warningf(fmt.Sprintf("%sConflictRetried", gvk.Kind), "Retrying update of %s after conflict (attempt %d/%d)", resourcehelper.FormatResourceForCLIWithNamespace(required), attempt, a.maxConflictRetries)
+
+		result, err = a.client.Update(ctx, retryUpdate, metav1.UpdateOptions{})
+		return err
+	})
+
+	return result, modified, retryErr
+}
+
+// ApplyDryRun mirrors Apply but issues the Create/Update calls with
+// DryRunAll, so it reports what would change without persisting anything.
+func (a *applier[T]) ApplyDryRun(ctx context.Context, required T) (runtime.Object, bool, error) {
+	requiredAccessor, err := meta.Accessor(required)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing, err := a.getter.Get(requiredAccessor.GetName())
+	if errors.IsNotFound(err) {
+		actual, createErr := a.client.Create(ctx, required, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+		return actual, true, createErr
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	updated, modified := a.compare(required, existing)
+	if !modified {
+		return updated, false, nil
 	}
 
+	updated, err = a.client.Update(ctx, updated, metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}})
 	return updated, modified, err
 }
@@ -0,0 +1,154 @@
+package apply
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type fakeConfigMapGetter struct {
+	obj *corev1.ConfigMap
+	err error
+}
+
+func (g *fakeConfigMapGetter) Get(name string) (*corev1.ConfigMap, error) {
+	if g.err != nil {
+		return nil, g.err
+	}
+	return g.obj, nil
+}
+
+// sequenceConfigMapGetter returns each entry in objs in turn, repeating the
+// last entry once exhausted, so a test can simulate a competing write
+// landing between an Apply's initial Get and a conflict retry's re-Get.
+type sequenceConfigMapGetter struct {
+	objs []*corev1.ConfigMap
+	call int
+}
+
+func (g *sequenceConfigMapGetter) Get(name string) (*corev1.ConfigMap, error) {
+	i := g.call
+	if i >= len(g.objs) {
+		i = len(g.objs) - 1
+	}
+	g.call++
+	return g.objs[i], nil
+}
+
+// conflictThenSucceedClient returns a conflict from Update the first
+// conflictsLeft times it's called, then succeeds.
+type conflictThenSucceedClient struct {
+	conflictsLeft int
+	updates       []*corev1.ConfigMap
+}
+
+func (c *conflictThenSucceedClient) Create(_ context.Context, obj *corev1.ConfigMap, _ metav1.CreateOptions) (*corev1.ConfigMap, error) {
+	return obj, nil
+}
+
+func (c *conflictThenSucceedClient) Update(_ context.Context, obj *corev1.ConfigMap, _ metav1.UpdateOptions) (*corev1.ConfigMap, error) {
+	c.updates = append(c.updates, obj)
+	if c.conflictsLeft > 0 {
+		c.conflictsLeft--
+		return nil, errors.NewConflict(schema.GroupResource{Resource: "configmaps"}, obj.Name, nil)
+	}
+	return obj, nil
+}
+
+func noBackoffOpt() ApplierOption[*corev1.ConfigMap] {
+	return WithBackoff[*corev1.ConfigMap](wait.Backoff{Duration: time.Microsecond, Factor: 1, Steps: defaultMaxConflictRetries})
+}
+
+func TestApplyRetriesOnConflictAndEventuallySucceeds(t *testing.T) {
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm"},
+		Data:       map[string]string{"a": "1"},
+	}
+	required := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm"},
+		Data:       map[string]string{"a": "2"},
+	}
+
+	getter := &fakeConfigMapGetter{obj: existing}
+	client := &conflictThenSucceedClient{conflictsLeft: 2}
+	compare := func(required, existing *corev1.ConfigMap) (*corev1.ConfigMap, bool) {
+		if required.Data["a"] == existing.Data["a"] {
+			return existing, false
+		}
+		return required, true
+	}
+
+	applier := NewApplier[*corev1.ConfigMap](getter, client, compare, noBackoffOpt())
+
+	recorder := newRecorder()
+	actual, modified, err := applier.Apply(context.Background(), required, recorder)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !modified {
+		t.Fatalf("expected modified to be true")
+	}
+	if actual.(*corev1.ConfigMap).Data["a"] != "2" {
+		t.Fatalf("expected the update to have gone through, got %v", actual)
+	}
+	if len(client.updates) != 3 {
+		t.Fatalf("expected 1 initial Update + 2 retries, got %d calls", len(client.updates))
+	}
+}
+
+func TestApplyConflictResolvedByCompetingWriteReportsUnmodified(t *testing.T) {
+	// The first Get (used for Apply's initial compare) sees stale data that
+	// still needs required's change; the second Get (used by the conflict
+	// retry) sees a competing controller having already applied it.
+	stale := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}, Data: map[string]string{"a": "1"}}
+	already := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}, Data: map[string]string{"a": "2"}}
+	required := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}, Data: map[string]string{"a": "2"}}
+
+	getter := &sequenceConfigMapGetter{objs: []*corev1.ConfigMap{stale, already}}
+	client := &conflictThenSucceedClient{conflictsLeft: 1}
+	compare := func(required, existing *corev1.ConfigMap) (*corev1.ConfigMap, bool) {
+		if required.Data["a"] == existing.Data["a"] {
+			return existing, false
+		}
+		return required, true
+	}
+
+	applier := NewApplier[*corev1.ConfigMap](getter, client, compare, noBackoffOpt())
+
+	recorder := newRecorder()
+	_, modified, err := applier.Apply(context.Background(), required, recorder)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if modified {
+		t.Fatalf("expected modified to be false once the conflict retry finds nothing left to apply")
+	}
+
+	for _, event := range recorder.Events() {
+		if event.Reason == "ConfigMapUpdated" {
+			t.Fatalf("did not expect an Updated event when the retry found no remaining change: %v", event)
+		}
+	}
+}
+
+func TestApplyNoConflictRetryWhenDisabled(t *testing.T) {
+	existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}, Data: map[string]string{"a": "1"}}
+	required := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}, Data: map[string]string{"a": "2"}}
+
+	getter := &fakeConfigMapGetter{obj: existing}
+	client := &conflictThenSucceedClient{conflictsLeft: 1}
+	compare := func(required, existing *corev1.ConfigMap) (*corev1.ConfigMap, bool) { return required, true }
+
+	applier := NewApplier[*corev1.ConfigMap](getter, client, compare, WithConflictRetry[*corev1.ConfigMap](0))
+
+	_, _, err := applier.Apply(context.Background(), required, newRecorder())
+	if err == nil || !errors.IsConflict(err) {
+		t.Fatalf("expected the conflict to bubble straight back up, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// InventoryOwnerIDLabel is set on every inventory ConfigMap to the ownerID it
+// tracks, following the cli-utils convention of labelling the inventory
+// object with the identity of whatever owns it.
+const InventoryOwnerIDLabel = "apply.open-cluster-management.io/inventory-owner"
+
+// inventoryDataKey is the ConfigMap data key the JSON-encoded inventory is
+// stored under.
+const inventoryDataKey = "inventory"
+
+// ConfigMapInventoryClient is an InventoryClient that records inventory as
+// JSON in a single ConfigMap per owner, named "<ownerID>-inventory" and
+// labelled with InventoryOwnerIDLabel, mirroring the cli-utils inventory
+// pattern. Deleting a tracked object is issued through dynamicClient, since a
+// single inventory can span arbitrary GVKs; restMapper resolves each
+// InventoryItem's GVK to the GVR dynamicClient needs.
+type ConfigMapInventoryClient struct {
+	namespace     string
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	restMapper    meta.RESTMapper
+}
+
+// NewConfigMapInventoryClient returns a ConfigMapInventoryClient that stores
+// its inventory ConfigMaps in namespace.
+func NewConfigMapInventoryClient(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, namespace string) *ConfigMapInventoryClient {
+	return &ConfigMapInventoryClient{
+		namespace:     namespace,
+		kubeClient:    kubeClient,
+		dynamicClient: dynamicClient,
+		restMapper:    restMapper,
+	}
+}
+
+func inventoryConfigMapName(ownerID string) string {
+	return fmt.Sprintf("%s-inventory", ownerID)
+}
+
+// GetInventory implements InventoryClient.
+func (c *ConfigMapInventoryClient) GetInventory(ctx context.Context, ownerID string) ([]InventoryItem, error) {
+	cm, err := c.kubeClient.CoreV1().ConfigMaps(c.namespace).Get(ctx, inventoryConfigMapName(ownerID), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := cm.Data[inventoryDataKey]
+	if !ok || data == "" {
+		return nil, nil
+	}
+
+	var items []InventoryItem
+	if err := json.Unmarshal([]byte(data), &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// SetInventory implements InventoryClient.
+func (c *ConfigMapInventoryClient) SetInventory(ctx context.Context, ownerID string, items []InventoryItem) error {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	name := inventoryConfigMapName(ownerID)
+	existing, err := c.kubeClient.CoreV1().ConfigMaps(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, createErr := c.kubeClient.CoreV1().ConfigMaps(c.namespace).Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: c.namespace,
+				Labels:    map[string]string{InventoryOwnerIDLabel: ownerID},
+			},
+			Data: map[string]string{inventoryDataKey: string(data)},
+		}, metav1.CreateOptions{})
+		return createErr
+	}
+	if err != nil {
+		return err
+	}
+
+	updated := existing.DeepCopy()
+	if updated.Labels == nil {
+		updated.Labels = map[string]string{}
+	}
+	updated.Labels[InventoryOwnerIDLabel] = ownerID
+	if updated.Data == nil {
+		updated.Data = map[string]string{}
+	}
+	updated.Data[inventoryDataKey] = string(data)
+
+	_, err = c.kubeClient.CoreV1().ConfigMaps(c.namespace).Update(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+// Delete implements InventoryClient by resolving item's GVK to a GVR through
+// restMapper and foreground-deleting it through dynamicClient.
+func (c *ConfigMapInventoryClient) Delete(ctx context.Context, item InventoryItem) error {
+	mapping, err := c.restMapper.RESTMapping(item.GVK.GroupKind(), item.GVK.Version)
+	if err != nil {
+		return err
+	}
+
+	resourceClient := c.dynamicClient.Resource(mapping.Resource)
+	var client dynamic.ResourceInterface = resourceClient
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		client = resourceClient.Namespace(item.Namespace)
+	}
+
+	foreground := metav1.DeletePropagationForeground
+	return client.Delete(ctx, item.Name, metav1.DeleteOptions{PropagationPolicy: &foreground})
+}
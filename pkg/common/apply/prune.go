@@ -0,0 +1,170 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourcehelper"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// InventoryItem is the GVK/namespace/name tuple recorded for a single object
+// that an Applier applied, so it can be found and pruned again later even
+// after the desired manifest set stops mentioning it.
+type InventoryItem struct {
+	GVK       schema.GroupVersionKind
+	Namespace string
+	Name      string
+}
+
+func (i InventoryItem) key() string {
+	return fmt.Sprintf("%s/%s/%s", i.GVK.String(), i.Namespace, i.Name)
+}
+
+func (i InventoryItem) asObject() runtime.Object {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(i.GVK)
+	u.SetNamespace(i.Namespace)
+	u.SetName(i.Name)
+	return u
+}
+
+// InventoryClient stores and retrieves the inventory recorded for an
+// owner, and deletes objects it describes. Implementations typically back
+// this onto a single well-known ConfigMap per owner, following the cli-utils
+// inventory pattern.
+type InventoryClient interface {
+	// GetInventory returns the items recorded for ownerID, or an empty slice
+	// if nothing has been recorded yet.
+	GetInventory(ctx context.Context, ownerID string) ([]InventoryItem, error)
+
+	// SetInventory overwrites the items recorded for ownerID.
+	SetInventory(ctx context.Context, ownerID string, items []InventoryItem) error
+
+	// Delete removes the object described by item.
+	Delete(ctx context.Context, item InventoryItem) error
+}
+
+// Pruner deletes objects that were applied for an owner on a previous
+// reconcile but are no longer part of its current desired set.
+type Pruner struct {
+	inventory InventoryClient
+	ownerID   string
+}
+
+// NewPruner returns a Pruner that reads and writes the inventory recorded
+// for ownerID through inventory.
+func NewPruner(inventory InventoryClient, ownerID string) *Pruner {
+	return &Pruner{inventory: inventory, ownerID: ownerID}
+}
+
+// Prune deletes anything recorded in the previous inventory for the owner
+// that isn't present in current, then records current as the new inventory.
+// If any deletion fails, the aggregated errors are returned and the previous
+// inventory is left as it is. Deletions are foreground (best-effort here; callers using a client that
+// supports it should pass DeletePropagationForeground through their
+// InventoryClient.Delete implementation) so dependents are cleaned up before
+// the owner reference disappears.
+func (p *Pruner) Prune(ctx context.Context, recorder events.Recorder, current []InventoryItem) error {
+	previous, err := p.inventory.GetInventory(ctx, p.ownerID)
+	if err != nil {
+		return err
+	}
+
+	desired := make(map[string]bool, len(current))
+	for _, item := range current {
+		desired[item.key()] = true
+	}
+
+	var errs []error
+	for _, item := range previous {
+		if desired[item.key()] {
+			continue
+		}
+
+		if err := p.inventory.Delete(ctx, item); err != nil && !errors.IsNotFound(err) {
+			recorder.Warningf(fmt.Sprintf("%sPruneFailed", item.GVK.Kind), "Failed to prune %s: %v", resourcehelper.FormatResourceForCLIWithNamespace(item.asObject()), err)
+			errs = append(errs, err)
+			continue
+		}
+
+		recorder.Eventf(fmt.Sprintf("%sPruned", item.GVK.Kind), "Pruned %s because it is no longer part of the desired state", resourcehelper.FormatResourceForCLIWithNamespace(item.asObject()))
+	}
+
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	return p.inventory.SetInventory(ctx, p.ownerID, current)
+}
+
+// ApplierWithInventory wraps an Applier so every object it successfully
+// applies is recorded in memory, and a later call to Prune can delete anything
+// dropped from the desired set since the last reconcile and write the recorded
+// objects as the new inventory through its InventoryClient.
+type ApplierWithInventory[T runtime.Object] struct {
+	Applier[T]
+
+	pruner *Pruner
+
+	mu      sync.Mutex
+	applied []InventoryItem
+}
+
+// NewApplierWithInventory returns an ApplierWithInventory backed by getter,
+// client and compare for applying, and by inventory for tracking what has
+// been applied under ownerID.
+func NewApplierWithInventory[T runtime.Object](getter Getter[T], client Client[T], compare CompareFunc[T], inventory InventoryClient, ownerID string) *ApplierWithInventory[T] {
+	return &ApplierWithInventory[T]{
+		Applier: NewApplier(getter, client, compare),
+		pruner:  NewPruner(inventory, ownerID),
+	}
+}
+
+// Apply applies required and, on success, records it as part of this
+// reconcile's inventory so a subsequent Prune call won't delete it. Apply may
+// be called concurrently, e.g. by a BundleApplier using WithWorkers, so
+// access to the accumulated inventory is synchronized.
+func (a *ApplierWithInventory[T]) Apply(ctx context.Context, required T, recorder events.Recorder) (runtime.Object, bool, error) {
+	actual, modified, err := a.Applier.Apply(ctx, required, recorder)
+	if err != nil {
+		return actual, modified, err
+	}
+
+	actualAccessor, err := meta.Accessor(actual)
+	if err != nil {
+		return actual, modified, err
+	}
+
+	item := InventoryItem{
+		GVK:       resourcehelper.GuessObjectGroupVersionKind(required),
+		Namespace: actualAccessor.GetNamespace(),
+		Name:      actualAccessor.GetName(),
+	}
+
+	a.mu.Lock()
+	a.applied = append(a.applied, item)
+	a.mu.Unlock()
+
+	return actual, modified, nil
+}
+
+// Prune deletes anything recorded in the inventory from a previous reconcile
+// that wasn't applied again through Apply since, and records the objects
+// applied since the previous Prune as the new inventory. Each call starts a
+// fresh record for the next reconcile.
+func (a *ApplierWithInventory[T]) Prune(ctx context.Context, recorder events.Recorder) error {
+	a.mu.Lock()
+	applied := a.applied
+	a.applied = nil
+	a.mu.Unlock()
+
+	return a.pruner.Prune(ctx, recorder, applied)
+}
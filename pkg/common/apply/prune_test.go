@@ -0,0 +1,130 @@
+package apply
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+type fakeInventoryClient struct {
+	mu      sync.Mutex
+	items   map[string][]InventoryItem
+	deleted []InventoryItem
+}
+
+func newFakeInventoryClient(initial []InventoryItem) *fakeInventoryClient {
+	return &fakeInventoryClient{items: map[string][]InventoryItem{"owner": initial}}
+}
+
+func (f *fakeInventoryClient) GetInventory(_ context.Context, ownerID string) ([]InventoryItem, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]InventoryItem{}, f.items[ownerID]...), nil
+}
+
+func (f *fakeInventoryClient) SetInventory(_ context.Context, ownerID string, items []InventoryItem) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items[ownerID] = append([]InventoryItem{}, items...)
+	return nil
+}
+
+func (f *fakeInventoryClient) Delete(_ context.Context, item InventoryItem) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleted = append(f.deleted, item)
+	return nil
+}
+
+func newRecorder() events.InMemoryRecorder {
+	return events.NewInMemoryRecorder("test", clocktesting.NewFakePassiveClock(time.Now()))
+}
+
+func TestPrunerDeletesDroppedItems(t *testing.T) {
+	cmGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	keep := InventoryItem{GVK: cmGVK, Namespace: "ns", Name: "keep"}
+	drop := InventoryItem{GVK: cmGVK, Namespace: "ns", Name: "drop"}
+
+	client := newFakeInventoryClient([]InventoryItem{keep, drop})
+	pruner := NewPruner(client, "owner")
+
+	if err := pruner.Prune(context.Background(), newRecorder(), []InventoryItem{keep}); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+
+	if len(client.deleted) != 1 || client.deleted[0] != drop {
+		t.Fatalf("expected only %v to be deleted, got %v", drop, client.deleted)
+	}
+
+	current, err := client.GetInventory(context.Background(), "owner")
+	if err != nil {
+		t.Fatalf("GetInventory: %v", err)
+	}
+	if len(current) != 1 || current[0] != keep {
+		t.Fatalf("expected inventory to be reset to %v, got %v", keep, current)
+	}
+}
+
+type singleObjectGetter struct {
+	obj runtime.Object
+}
+
+func (g *singleObjectGetter) Get(name string) (runtime.Object, error) {
+	if g.obj == nil {
+		return nil, errors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name)
+	}
+	return g.obj, nil
+}
+
+type recordingClient struct {
+	mu      sync.Mutex
+	applied int
+}
+
+func (c *recordingClient) Create(_ context.Context, obj runtime.Object, _ metav1.CreateOptions) (runtime.Object, error) {
+	c.mu.Lock()
+	c.applied++
+	c.mu.Unlock()
+	return obj, nil
+}
+
+func (c *recordingClient) Update(_ context.Context, obj runtime.Object, _ metav1.UpdateOptions) (runtime.Object, error) {
+	c.mu.Lock()
+	c.applied++
+	c.mu.Unlock()
+	return obj, nil
+}
+
+func TestApplierWithInventoryConcurrentApply(t *testing.T) {
+	getter := &singleObjectGetter{}
+	client := &recordingClient{}
+	compare := func(required, existing runtime.Object) (runtime.Object, bool) { return required, true }
+
+	a := NewApplierWithInventory[runtime.Object](getter, client, compare, newFakeInventoryClient(nil), "owner")
+
+	const n = 50
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := a.Apply(context.Background(), &metav1.PartialObjectMetadata{}, newRecorder())
+			if err != nil {
+				t.Errorf("Apply: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(a.applied) != n {
+		t.Fatalf("expected %d recorded inventory items, got %d", n, len(a.applied))
+	}
+}
@@ -0,0 +1,153 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourcehelper"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Patcher is a wrapper interface around the Patch call of a typed clientset or
+// a dynamic client, so ssaApplier can back onto either.
+type Patcher[T runtime.Object] interface {
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions) (T, error)
+}
+
+// ssaApplier implements Applier using Kubernetes Server-Side Apply instead of
+// the Get->compare->Update flow used by applier.
+type ssaApplier[T runtime.Object] struct {
+	getter       Getter[T]
+	patcher      Patcher[T]
+	fieldManager string
+}
+
+// NewSSAApplier returns an Applier that applies required via Server-Side
+// Apply: it marshals required into an apply-patch and Patches it with Force
+// set to true under fieldManager, rather than computing the update itself
+// with a CompareFunc. This avoids the merge bugs and lost-field problems that
+// come from hand-rolled three-way comparisons.
+func NewSSAApplier[T runtime.Object](getter Getter[T], patcher Patcher[T], fieldManager string) Applier[T] {
+	return &ssaApplier[T]{
+		getter:       getter,
+		patcher:      patcher,
+		fieldManager: fieldManager,
+	}
+}
+
+func (a *ssaApplier[T]) Apply(ctx context.Context, required T, recorder events.Recorder) (runtime.Object, bool, error) {
+	requiredAccessor, err := meta.Accessor(required)
+	if err != nil {
+		return nil, false, err
+	}
+	gvk := resourcehelper.GuessObjectGroupVersionKind(required)
+
+	var existingResourceVersion, existingGeneration string
+	existing, err := a.getter.Get(requiredAccessor.GetName())
+	switch {
+	case errors.IsNotFound(err):
+		// existingResourceVersion stays empty, which is how we tell Create
+		// apart from Update below.
+	case err != nil:
+		return nil, false, err
+	default:
+		existingAccessor, accessorErr := meta.Accessor(existing)
+		if accessorErr != nil {
+			return nil, false, accessorErr
+		}
+		existingResourceVersion = existingAccessor.GetResourceVersion()
+		existingGeneration = fmt.Sprintf("%d", existingAccessor.GetGeneration())
+	}
+
+	data, err := yaml.Marshal(required)
+	if err != nil {
+		return nil, false, err
+	}
+
+	force := true
+	actual, err := a.patcher.Patch(ctx, requiredAccessor.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+		FieldManager: a.fieldManager,
+		Force:        &force,
+	})
+	if err != nil {
+		recorder.Warningf(fmt.Sprintf("%sApplyFailed", gvk.Kind), "Failed to apply %s: %v", resourcehelper.FormatResourceForCLIWithNamespace(required), err)
+		return nil, false, err
+	}
+
+	actualAccessor, err := meta.Accessor(actual)
+	if err != nil {
+		return nil, false, err
+	}
+
+	created := existingResourceVersion == ""
+	modified := created ||
+		actualAccessor.GetResourceVersion() != existingResourceVersion ||
+		fmt.Sprintf("%d", actualAccessor.GetGeneration()) != existingGeneration
+	if !modified {
+		return actual, false, nil
+	}
+
+	if created {
+		recorder.Eventf(fmt.Sprintf("%sCreated", gvk.Kind), "Created %s because it was missing", resourcehelper.FormatResourceForCLIWithNamespace(actual))
+	} else {
+		recorder.Eventf(fmt.Sprintf("%sUpdated", gvk.Kind), "Updated %s", resourcehelper.FormatResourceForCLIWithNamespace(actual))
+	}
+
+	return actual, true, nil
+}
+
+// ApplyDryRun mirrors Apply but Patches with DryRunAll, so it reports the
+// object Server-Side Apply would produce without persisting it.
+func (a *ssaApplier[T]) ApplyDryRun(ctx context.Context, required T) (runtime.Object, bool, error) {
+	requiredAccessor, err := meta.Accessor(required)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var existingResourceVersion, existingGeneration string
+	existing, err := a.getter.Get(requiredAccessor.GetName())
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return nil, false, err
+	default:
+		existingAccessor, accessorErr := meta.Accessor(existing)
+		if accessorErr != nil {
+			return nil, false, accessorErr
+		}
+		existingResourceVersion = existingAccessor.GetResourceVersion()
+		existingGeneration = fmt.Sprintf("%d", existingAccessor.GetGeneration())
+	}
+
+	data, err := yaml.Marshal(required)
+	if err != nil {
+		return nil, false, err
+	}
+
+	force := true
+	actual, err := a.patcher.Patch(ctx, requiredAccessor.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+		FieldManager: a.fieldManager,
+		Force:        &force,
+		DryRun:       []string{metav1.DryRunAll},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	actualAccessor, err := meta.Accessor(actual)
+	if err != nil {
+		return nil, false, err
+	}
+
+	modified := existingResourceVersion == "" ||
+		actualAccessor.GetResourceVersion() != existingResourceVersion ||
+		fmt.Sprintf("%d", actualAccessor.GetGeneration()) != existingGeneration
+
+	return actual, modified, nil
+}
@@ -0,0 +1,142 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// fakeSSAPatcher mimics how a real apiserver handles Server-Side Apply: the
+// returned object's ResourceVersion/Generation only change if the patch
+// actually altered the data, and a dry-run Patch reports what the result
+// would be without persisting it.
+type fakeSSAPatcher struct {
+	current *corev1.ConfigMap
+	version int
+	calls   []metav1.PatchOptions
+}
+
+func (p *fakeSSAPatcher) Patch(_ context.Context, name string, _ types.PatchType, data []byte, opts metav1.PatchOptions) (*corev1.ConfigMap, error) {
+	p.calls = append(p.calls, opts)
+
+	obj := &corev1.ConfigMap{}
+	if err := yaml.Unmarshal(data, obj); err != nil {
+		return nil, err
+	}
+
+	changed := p.current == nil || !equalStringMaps(p.current.Data, obj.Data)
+	version := p.version
+	if changed {
+		version++
+	}
+
+	if len(opts.DryRun) > 0 {
+		obj.ResourceVersion = fmt.Sprintf("%d", version)
+		obj.Generation = int64(version)
+		return obj, nil
+	}
+
+	p.version = version
+	obj.ResourceVersion = fmt.Sprintf("%d", version)
+	obj.Generation = int64(version)
+	p.current = obj
+	return obj, nil
+}
+
+type ssaGetter struct {
+	obj *corev1.ConfigMap
+}
+
+func (g *ssaGetter) Get(name string) (*corev1.ConfigMap, error) {
+	if g.obj == nil {
+		return nil, errors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name)
+	}
+	return g.obj, nil
+}
+
+func TestSSAApplierCreatesWhenMissing(t *testing.T) {
+	getter := &ssaGetter{}
+	patcher := &fakeSSAPatcher{}
+	applier := NewSSAApplier[*corev1.ConfigMap](getter, patcher, "test-controller")
+
+	required := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}, Data: map[string]string{"a": "1"}}
+
+	recorder := newRecorder()
+	actual, modified, err := applier.Apply(context.Background(), required, recorder)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !modified {
+		t.Fatalf("expected modified to be true on create")
+	}
+	if actual.(*corev1.ConfigMap).Data["a"] != "1" {
+		t.Fatalf("unexpected object: %v", actual)
+	}
+
+	var sawCreated bool
+	for _, event := range recorder.Events() {
+		if event.Reason == "ConfigMapCreated" {
+			sawCreated = true
+		}
+	}
+	if !sawCreated {
+		t.Fatalf("expected a ConfigMapCreated event, got %v", recorder.Events())
+	}
+	if patcher.calls[0].Force == nil || !*patcher.calls[0].Force {
+		t.Fatalf("expected Patch to be called with Force: true")
+	}
+}
+
+func TestSSAApplierReportsUnmodifiedWhenNothingChanges(t *testing.T) {
+	existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", ResourceVersion: "0"}, Data: map[string]string{"a": "1"}}
+	getter := &ssaGetter{obj: existing}
+	patcher := &fakeSSAPatcher{current: existing}
+	applier := NewSSAApplier[*corev1.ConfigMap](getter, patcher, "test-controller")
+
+	required := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}, Data: map[string]string{"a": "1"}}
+
+	_, modified, err := applier.Apply(context.Background(), required, newRecorder())
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if modified {
+		t.Fatalf("expected modified to be false when the apiserver reports no change")
+	}
+}
+
+func TestSSAApplierDryRunDoesNotPersist(t *testing.T) {
+	existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", ResourceVersion: "0"}, Data: map[string]string{"a": "1"}}
+	getter := &ssaGetter{obj: existing}
+	patcher := &fakeSSAPatcher{current: existing}
+	applier := NewSSAApplier[*corev1.ConfigMap](getter, patcher, "test-controller")
+	dryRunApplier, ok := applier.(DryRunApplier[*corev1.ConfigMap])
+	if !ok {
+		t.Fatalf("expected ssaApplier to implement DryRunApplier")
+	}
+
+	required := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}, Data: map[string]string{"a": "2"}}
+
+	actual, modified, err := dryRunApplier.ApplyDryRun(context.Background(), required)
+	if err != nil {
+		t.Fatalf("ApplyDryRun: %v", err)
+	}
+	if !modified {
+		t.Fatalf("expected modified to be true")
+	}
+	if actual.(*corev1.ConfigMap).Data["a"] != "2" {
+		t.Fatalf("expected the dry-run result to reflect the requested change, got %v", actual)
+	}
+	if patcher.version != 0 {
+		t.Fatalf("expected the dry-run Patch to never persist, but patcher recorded %d real updates", patcher.version)
+	}
+	if len(patcher.calls) != 1 || len(patcher.calls[0].DryRun) == 0 {
+		t.Fatalf("expected Patch to be called with DryRunAll, got %v", patcher.calls)
+	}
+}